@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"sort"
+)
+
+// 阈值为负数表示该条件未被用户指定，不参与过滤。
+const unsetThreshold = -1
+
+var (
+	maxMeanLatencyMs = flag.Float64("tl", unsetThreshold, "-tl 最大平均延迟(ms)，超过此值的服务器被剔除，默认不限制")
+	minMeanLatencyMs = flag.Float64("tll", unsetThreshold, "-tll 最小平均延迟(ms)，低于此值的服务器被剔除，默认不限制")
+	minQPS           = flag.Float64("sl", unsetThreshold, "-sl 最小 QPS，低于此值的服务器被剔除，默认不限制")
+	topN             = flag.Int("dn", 0, "-dn 只保留排名前 N 的服务器，0 表示不限制")
+	latencyOnlyMode  = flag.Bool("dd", false, "-dd 只进行延迟测试，跳过 QPS/吞吐量阶段")
+)
+
+// FilterConditions 描述一组可任意组合的后置过滤阈值，语义与
+// CloudflareSpeedTest 一致：任意子集可被指定，结果必须同时满足所有已
+// 指定的条件；若满足条件的服务器数量不足 TopN，则返回实际满足的数量，
+// 而不是用占位数据填充。
+type FilterConditions struct {
+	MaxMeanLatencyMs float64 // ms，unsetThreshold 表示不限制
+	MinMeanLatencyMs float64 // ms，unsetThreshold 表示不限制
+	MaxLossRate      float64 // 0.0-1.0，unsetThreshold 表示不限制
+	MinQPS           float64 // unsetThreshold 表示不限制
+	TopN             int     // 0 表示不限制
+}
+
+// FilterConditionsFromFlags 从已解析的 CLI 标志构造 FilterConditions，
+// -tlr 复用丢包率硬过滤标志（参见 loss.go）。
+func FilterConditionsFromFlags() FilterConditions {
+	maxLoss := float64(unsetThreshold)
+	if *maxLossRate < 1.0 {
+		maxLoss = *maxLossRate
+	}
+	return FilterConditions{
+		MaxMeanLatencyMs: *maxMeanLatencyMs,
+		MinMeanLatencyMs: *minMeanLatencyMs,
+		MaxLossRate:      maxLoss,
+		MinQPS:           *minQPS,
+		TopN:             *topN,
+	}
+}
+
+// Filter 对已评分的结果应用 FilterConditions，裁剪掉任何不满足已指定
+// 阈值的服务器，再按 Total 从高到低排序，最后截断到 TopN（若设置）。
+// 调用方不需要预先排序：TopN 取的是过滤后按总分排名的前 N 个，
+// 而不是输入切片里排在前面的 N 个。
+func Filter(results []scoreResult, cond FilterConditions) []scoreResult {
+	filtered := make([]scoreResult, 0, len(results))
+	for _, r := range results {
+		if !satisfies(r, cond) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Total > filtered[j].Total
+	})
+
+	if cond.TopN > 0 && len(filtered) > cond.TopN {
+		filtered = filtered[:cond.TopN]
+	}
+	return filtered
+}
+
+func satisfies(r scoreResult, cond FilterConditions) bool {
+	meanMS := float64(r.Source.LatencyStats.MeanMs)
+	if cond.MaxMeanLatencyMs != unsetThreshold && meanMS > cond.MaxMeanLatencyMs {
+		return false
+	}
+	if cond.MinMeanLatencyMs != unsetThreshold && meanMS < cond.MinMeanLatencyMs {
+		return false
+	}
+	if cond.MaxLossRate != unsetThreshold {
+		// 复用 loss.go 中的 lossRate，与 FilterByLossRate 共享同一个
+		// -tlr 阈值判定，避免零请求这一边界情况在两层过滤中各判各的：
+		// 没有样本可供判断时同样视为未通过（与 FilterByLossRate 一致）。
+		rate, ok := lossRate(r.Source)
+		if !ok || rate > cond.MaxLossRate {
+			return false
+		}
+	}
+	if cond.MinQPS != unsetThreshold && r.Source.QueriesPerSecond < cond.MinQPS {
+		return false
+	}
+	return true
+}
+
+// LatencyOnlyMode 报告用户是否传入了 -dd，即只测延迟、跳过 QPS/吞吐量
+// 阶段。基准测试驱动代码应在发起压测前检查该值。
+func LatencyOnlyMode() bool {
+	return *latencyOnlyMode
+}
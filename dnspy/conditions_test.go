@@ -0,0 +1,81 @@
+package main
+
+import "testing"
+
+func newScoreResult(meanMS, qps, total float64, timeouts, requests int64) scoreResult {
+	return scoreResult{
+		Total: total,
+		Source: jsonResult{
+			TotalRequests:    requests,
+			TotalTimeouts:    timeouts,
+			QueriesPerSecond: qps,
+			LatencyStats:     LatencyStats{MeanMs: meanMS},
+		},
+	}
+}
+
+func TestSatisfiesThresholds(t *testing.T) {
+	r := newScoreResult(20, 50, 90, 1, 100) // 1% loss
+
+	cases := []struct {
+		name string
+		cond FilterConditions
+		want bool
+	}{
+		{"no conditions", FilterConditions{MaxMeanLatencyMs: unsetThreshold, MinMeanLatencyMs: unsetThreshold, MaxLossRate: unsetThreshold, MinQPS: unsetThreshold}, true},
+		{"max latency ok", FilterConditions{MaxMeanLatencyMs: 30, MinMeanLatencyMs: unsetThreshold, MaxLossRate: unsetThreshold, MinQPS: unsetThreshold}, true},
+		{"max latency fails", FilterConditions{MaxMeanLatencyMs: 10, MinMeanLatencyMs: unsetThreshold, MaxLossRate: unsetThreshold, MinQPS: unsetThreshold}, false},
+		{"min latency fails", FilterConditions{MaxMeanLatencyMs: unsetThreshold, MinMeanLatencyMs: 25, MaxLossRate: unsetThreshold, MinQPS: unsetThreshold}, false},
+		{"max loss fails", FilterConditions{MaxMeanLatencyMs: unsetThreshold, MinMeanLatencyMs: unsetThreshold, MaxLossRate: 0.005, MinQPS: unsetThreshold}, false},
+		{"min qps fails", FilterConditions{MaxMeanLatencyMs: unsetThreshold, MinMeanLatencyMs: unsetThreshold, MaxLossRate: unsetThreshold, MinQPS: 100}, false},
+	}
+
+	for _, c := range cases {
+		if got := satisfies(r, c.cond); got != c.want {
+			t.Errorf("%s: satisfies() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestFilterRanksByTotalBeforeTopN(t *testing.T) {
+	// Deliberately ordered so the lowest-scoring server comes first in the
+	// input slice; Filter must rank by Total before truncating to TopN.
+	results := []scoreResult{
+		newScoreResult(10, 50, 50, 0, 100),
+		newScoreResult(10, 50, 90, 0, 100),
+		newScoreResult(10, 50, 70, 0, 100),
+	}
+
+	got := Filter(results, FilterConditions{
+		MaxMeanLatencyMs: unsetThreshold,
+		MinMeanLatencyMs: unsetThreshold,
+		MaxLossRate:      unsetThreshold,
+		MinQPS:           unsetThreshold,
+		TopN:             2,
+	})
+
+	if len(got) != 2 {
+		t.Fatalf("len(Filter(...)) = %d, want 2", len(got))
+	}
+	if got[0].Total != 90 || got[1].Total != 70 {
+		t.Fatalf("Filter(...) totals = [%v, %v], want [90, 70]", got[0].Total, got[1].Total)
+	}
+}
+
+func TestFilterTopNShortfallReturnsWhatPassed(t *testing.T) {
+	results := []scoreResult{
+		newScoreResult(10, 50, 50, 0, 100),
+	}
+
+	got := Filter(results, FilterConditions{
+		MaxMeanLatencyMs: unsetThreshold,
+		MinMeanLatencyMs: unsetThreshold,
+		MaxLossRate:      unsetThreshold,
+		MinQPS:           unsetThreshold,
+		TopN:             5,
+	})
+
+	if len(got) != 1 {
+		t.Fatalf("len(Filter(...)) = %d, want 1", len(got))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import "flag"
+
+// 可用性判定相关的 CLI 参数，镜像 Android DNS resolver 的统计策略：
+// 最近 windowSize 次查询中，成功次数不足 minSamples 或成功率低于
+// successThreshold 的服务器被判定为 unusable。
+var (
+	minSamples       = flag.Int("min-samples", 8, "判定服务器可用性所需的最少样本数")
+	successThreshold = flag.Float64("success-threshold", 0.5, "判定服务器可用的最低成功率（0.0-1.0）")
+	windowSize       = flag.Int("window-size", 20, "滚动窗口内保留的最近样本数量")
+)
+
+// sampleOutcome 表示窗口内单次查询的结果，用于滚动统计成功率和平均 RTT。
+type sampleOutcome struct {
+	success bool
+	rttMs   float64
+}
+
+// usabilityStats 是对一组 sampleOutcome 窗口的汇总。
+type usabilityStats struct {
+	successes int
+	total     int
+	meanRTTMs float64
+}
+
+// computeUsabilityStats 在最近 window 个样本上计算成功率和平均 RTT。
+// window 超过 windowSize 的部分按调用方约定在采样阶段已被裁剪，此处只做汇总。
+func computeUsabilityStats(window []sampleOutcome) usabilityStats {
+	var stats usabilityStats
+	var rttSum float64
+
+	stats.total = len(window)
+	for _, o := range window {
+		if o.success {
+			stats.successes++
+			rttSum += o.rttMs
+		}
+	}
+	if stats.successes > 0 {
+		stats.meanRTTMs = rttSum / float64(stats.successes)
+	}
+	return stats
+}
+
+// isUnusable 根据 minSamples 和 successThreshold 判定服务器是否应被标记为不可用。
+func isUnusable(stats usabilityStats) bool {
+	if stats.successes < *minSamples {
+		return true
+	}
+	successRate := float64(stats.successes) / float64(stats.total)
+	return successRate < *successThreshold
+}
+
+// FilterUsableServers 在打分之前对一组 jsonResult 做可用性预筛选：标记
+// 成功率过低或成功次数不足 minSamples 的服务器为不可用。若筛选后所有服务器都不可用，
+// 则退化为“全部视为可用”，保证基准测试仍然返回一个完整的排名列表，
+// 而不是空结果。
+func FilterUsableServers(results []jsonResult) []scoreResult {
+	scored := make([]scoreResult, len(results))
+	allUnusable := true
+
+	for i, r := range results {
+		sr := ScoreBenchmarkResult(r)
+		window := recentOutcomes(r, *windowSize)
+		stats := computeUsabilityStats(window)
+		sr.Unusable = isUnusable(stats)
+		scored[i] = sr
+		if !sr.Unusable {
+			allUnusable = false
+		}
+	}
+
+	if allUnusable {
+		for i := range scored {
+			scored[i].Unusable = false
+		}
+	}
+
+	return scored
+}
+
+// recentOutcomes 取出 r 最近 n 次查询的结果，n 大于可用样本数时返回全部。
+func recentOutcomes(r jsonResult, n int) []sampleOutcome {
+	outcomes := r.Outcomes
+	if len(outcomes) <= n {
+		return outcomes
+	}
+	return outcomes[len(outcomes)-n:]
+}
@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+func TestComputeUsabilityStats(t *testing.T) {
+	window := []sampleOutcome{
+		{success: true, rttMs: 10},
+		{success: true, rttMs: 20},
+		{success: false},
+	}
+
+	stats := computeUsabilityStats(window)
+	if stats.total != 3 {
+		t.Fatalf("total = %d, want 3", stats.total)
+	}
+	if stats.successes != 2 {
+		t.Fatalf("successes = %d, want 2", stats.successes)
+	}
+	if stats.meanRTTMs != 15 {
+		t.Fatalf("meanRTTMs = %v, want 15", stats.meanRTTMs)
+	}
+}
+
+func TestIsUnusable(t *testing.T) {
+	cases := []struct {
+		name  string
+		stats usabilityStats
+		want  bool
+	}{
+		{"too few samples", usabilityStats{successes: 3, total: 3}, true},
+		{"enough samples, low success rate", usabilityStats{successes: 8, total: 20}, true},
+		{"enough samples, healthy success rate", usabilityStats{successes: 18, total: 20}, false},
+	}
+
+	for _, c := range cases {
+		if got := isUnusable(c.stats); got != c.want {
+			t.Errorf("%s: isUnusable(%+v) = %v, want %v", c.name, c.stats, got, c.want)
+		}
+	}
+}
+
+func newUsabilityResult(successOutcomes, failOutcomes int) jsonResult {
+	outcomes := make([]sampleOutcome, 0, successOutcomes+failOutcomes)
+	for i := 0; i < successOutcomes; i++ {
+		outcomes = append(outcomes, sampleOutcome{success: true, rttMs: 10})
+	}
+	for i := 0; i < failOutcomes; i++ {
+		outcomes = append(outcomes, sampleOutcome{success: false})
+	}
+	return jsonResult{
+		TotalRequests:         int64(successOutcomes + failOutcomes),
+		TotalSuccessResponses: int64(successOutcomes),
+		LatencyStats:          LatencyStats{MeanMs: 10},
+		Outcomes:              outcomes,
+	}
+}
+
+func TestFilterUsableServersMarksLowSuccessRate(t *testing.T) {
+	results := []jsonResult{
+		newUsabilityResult(18, 2), // healthy
+		newUsabilityResult(2, 18), // unhealthy
+	}
+
+	scored := FilterUsableServers(results)
+	if scored[0].Unusable {
+		t.Error("healthy server marked Unusable")
+	}
+	if !scored[1].Unusable {
+		t.Error("unhealthy server not marked Unusable")
+	}
+}
+
+func TestFilterUsableServersAllUnusableFallback(t *testing.T) {
+	results := []jsonResult{
+		newUsabilityResult(2, 18),
+		newUsabilityResult(1, 19),
+	}
+
+	scored := FilterUsableServers(results)
+	for i, sr := range scored {
+		if sr.Unusable {
+			t.Errorf("server %d still marked Unusable after all-unusable fallback", i)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "flag"
+
+// maxLossRate 是丢包率硬过滤阈值（-tlr），取值范围 0.0-1.0。
+// 实测丢包率超过该阈值的服务器会被整条从结果中剔除，而不仅仅是扣分，
+// 参照 CloudflareSpeedTest 的过滤方式。
+var maxLossRate = flag.Float64("tlr", 1.0, "丢包率上限（0.0-1.0），超过此值的服务器将被剔除，默认不过滤")
+
+// lossRate 计算 r 的丢包率（超时次数占总请求次数的比例）。当
+// TotalRequests 为 0 时没有样本可供判断，ok 返回 false。scoring
+// （rank.go）和两道过滤层（FilterByLossRate、satisfies 里的
+// MaxLossRate 检查，参见 conditions.go）都复用这一个实现，避免各处
+// 在零请求这个边界情况上产生不一致的判定。
+func lossRate(r jsonResult) (rate float64, ok bool) {
+	if r.TotalRequests == 0 {
+		return 0, false
+	}
+	return float64(r.TotalTimeouts) / float64(r.TotalRequests), true
+}
+
+// FilterByLossRate 剔除实测丢包率超过 maxLossRate 的服务器，以及没有
+// 任何请求样本、无法判断丢包率的服务器。
+func FilterByLossRate(results []jsonResult) []jsonResult {
+	if *maxLossRate >= 1.0 {
+		return results
+	}
+
+	filtered := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		rate, ok := lossRate(r)
+		if !ok || rate > *maxLossRate {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func newLossResult(timeouts, requests int64) jsonResult {
+	return jsonResult{TotalRequests: requests, TotalTimeouts: timeouts}
+}
+
+func TestLossRate(t *testing.T) {
+	if rate, ok := lossRate(newLossResult(0, 0)); ok || rate != 0 {
+		t.Fatalf("lossRate(zero requests) = (%v, %v), want (0, false)", rate, ok)
+	}
+
+	rate, ok := lossRate(newLossResult(5, 100))
+	if !ok {
+		t.Fatal("lossRate(5, 100): ok = false, want true")
+	}
+	if rate != 0.05 {
+		t.Fatalf("lossRate(5, 100) = %v, want 0.05", rate)
+	}
+}
+
+func TestFilterByLossRateDropsZeroRequestResults(t *testing.T) {
+	*maxLossRate = 0.5
+
+	results := []jsonResult{
+		newLossResult(0, 0),  // no samples -> dropped
+		newLossResult(1, 10), // 10% loss -> kept (0.1 <= 0.5)
+	}
+
+	filtered := FilterByLossRate(results)
+	if len(filtered) != 1 {
+		t.Fatalf("len(filtered) = %d, want 1", len(filtered))
+	}
+	if filtered[0].TotalRequests != 10 {
+		t.Fatalf("filtered[0].TotalRequests = %d, want 10", filtered[0].TotalRequests)
+	}
+}
+
+func TestFilterByLossRateAppliesThreshold(t *testing.T) {
+	*maxLossRate = 0.1
+
+	results := []jsonResult{
+		newLossResult(5, 100),  // 5% loss -> kept
+		newLossResult(50, 100), // 50% loss -> dropped
+	}
+
+	filtered := FilterByLossRate(results)
+	if len(filtered) != 1 {
+		t.Fatalf("len(filtered) = %d, want 1", len(filtered))
+	}
+	if filtered[0].TotalTimeouts != 5 {
+		t.Fatalf("filtered[0].TotalTimeouts = %d, want 5", filtered[0].TotalTimeouts)
+	}
+}
+
+func TestFilterByLossRateDisabledByDefault(t *testing.T) {
+	*maxLossRate = 1.0
+
+	results := []jsonResult{newLossResult(0, 0), newLossResult(100, 100)}
+	filtered := FilterByLossRate(results)
+	if len(filtered) != len(results) {
+		t.Fatalf("len(filtered) = %d, want %d (no filtering when maxLossRate >= 1.0)", len(filtered), len(results))
+	}
+}
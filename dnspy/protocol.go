@@ -0,0 +1,34 @@
+package main
+
+// Protocol 标识一次 DNS 查询使用的传输协议，决定了评分阶段如何看待
+// 握手/首字节开销。
+type Protocol string
+
+const (
+	ProtocolUDP Protocol = "udp"
+	ProtocolTCP Protocol = "tcp"
+	ProtocolDoT Protocol = "dot"
+	ProtocolDoH Protocol = "doh"
+	ProtocolDoQ Protocol = "doq"
+)
+
+// hasHandshakeCost 返回该协议是否包含一次性的 TLS/QUIC 握手开销，
+// 这部分开销在评分时应从延迟稳定性因子中剔除，避免首个请求的冷启动
+// 拖累整体评分。
+func (p Protocol) hasHandshakeCost() bool {
+	switch p {
+	case ProtocolDoT, ProtocolDoH, ProtocolDoQ:
+		return true
+	default:
+		return false
+	}
+}
+
+// latencyFullMarkPoint 返回该协议下延迟满分对应的毫秒数，握手类协议的
+// 满分阈值适当放宽，以反映其固有的首包开销。
+func (p Protocol) latencyFullMarkPoint() float64 {
+	if p.hasHandshakeCost() {
+		return LatencyFullMarkPoint * 2
+	}
+	return LatencyFullMarkPoint
+}
@@ -10,14 +10,25 @@ type scoreResult struct {
 	ErrorRate   float64 `json:"errorRate"`
 	Latency     float64 `json:"latency"`
 	Qps         float64 `json:"qps"`
+	LossRate    float64 `json:"lossRate"`
+	// Unusable 标记该服务器在最近的滚动窗口内成功次数不足 minSamples
+	// 或成功率过低，报告应将其
+	// 视觉降级展示而非直接归零，参见 FilterUsableServers。
+	Unusable bool `json:"unusable"`
+	// Source 保留原始的基准测试结果，供 Filter 层按原始延迟、QPS 等
+	// 指标做阈值过滤，不出现在 JSON 输出中。
+	Source jsonResult `json:"-"`
 }
 
-// 权重常量：用于不同评分项的权重
+// 权重常量：用于不同评分项的权重，总和为 100。
+// 新增 LossRateScoreWeight 后，成功率、错误率、延迟三项各下调 5，QPS 权重不变，
+// 以便将真实丢包与 rcode 错误区分开来单独计分。
 const (
-	SuccessRateScoreWeight = 25
-	ErrorRateScoreWeight   = 25
-	LatencyScoreWeight     = 40
+	SuccessRateScoreWeight = 20
+	ErrorRateScoreWeight   = 20
+	LatencyScoreWeight     = 35
 	QpsScoreWeight         = 10
+	LossRateScoreWeight    = 15
 )
 
 // 分数计算的常量阈值
@@ -43,24 +54,41 @@ func ScoreBenchmarkResult(r jsonResult) scoreResult {
 	// 计算成功率评分：线性映射
 	successRateScore := successRate * 100
 
-	// 计算错误率：错误响应和 IO 错误占总请求次数的比例
+	// 计算错误率：错误响应和 IO 错误占总请求次数的比例。
+	// TotalTimeouts 不再计入此处，真正的丢包单独计入 lossRate，
+	// 这样 rcode 错误和网络层丢包不会互相稀释对方的惩罚力度。
 	errorRate := float64(r.TotalErrorResponses+r.TotalIOErrors) / float64(r.TotalRequests)
 	// 错误率评分计算：
 	// 这种计算方法确保错误率越低，分数越高，且对高错误率的惩罚更加严重
 	// 随着错误率继续增加，分数下降会变得更快
 	errorRateScore := 100 / (1 + math.Pow(errorRate*100, 2))
 
-	// 计算延迟评分：综合平均延迟和标准差，考虑延迟的稳定性
+	// 计算丢包率：超时次数占总请求次数的比例，复用 loss.go 中的
+	// lossRate 辅助函数，避免各处对零请求边界情况的判定产生分歧。
+	// 对递归服务器而言，任何可观测的 UDP 丢包都是严重问题，因此惩罚
+	// 曲线比错误率更陡峭。此处 TotalRequests 已由上面的
+	// TotalSuccessResponses==0 提前返回保证非零。
+	lr, _ := lossRate(r)
+	lossRateScore := 100 * math.Pow(1-lr, 3)
+
+	// 计算延迟评分：综合平均延迟和标准差，考虑延迟的稳定性。
+	// 握手类协议（DoT/DoH/DoQ）使用更宽松的满分阈值，并且不把一次性的
+	// TLS/QUIC 握手开销计入稳定性惩罚，否则这些协议会被系统性地低估。
 	var latencyScore float64
 	meanMS := float64(r.LatencyStats.MeanMs)
+	fullMarkPoint := r.Protocol.latencyFullMarkPoint()
 	if meanMS < LatencyRangeMin || meanMS > LatencyRangeMax {
 		// 无效的平均延迟，得分为0
 		latencyScore = 0
 	} else {
 		// 如果平均延迟在满分阈值和 0.1ms 之间，线性计算分数
 		// 考虑标准差，引入惩罚因子，使得延迟波动大的情况得分更低
-		baseScore := 100 - (meanMS-LatencyFullMarkPoint)*100/(LatencyRangeMax-LatencyFullMarkPoint)
-		stabilityFactor := 1 - math.Min(1, float64(r.LatencyStats.StdMs)/meanMS)
+		baseScore := 100 - (meanMS-fullMarkPoint)*100/(LatencyRangeMax-fullMarkPoint)
+		stdMS := float64(r.LatencyStats.StdMs)
+		if r.Protocol.hasHandshakeCost() && stdMS > float64(r.HandshakeMs) {
+			stdMS -= float64(r.HandshakeMs)
+		}
+		stabilityFactor := 1 - math.Min(1, stdMS/meanMS)
 		latencyScore = baseScore * stabilityFactor
 	}
 	// 确保最终分数在0-100之间
@@ -78,7 +106,8 @@ func ScoreBenchmarkResult(r jsonResult) scoreResult {
 	totalScore := (successRateScore*SuccessRateScoreWeight +
 		errorRateScore*ErrorRateScoreWeight +
 		latencyScore*LatencyScoreWeight +
-		qpsScore*QpsScoreWeight) / 100
+		qpsScore*QpsScoreWeight +
+		lossRateScore*LossRateScoreWeight) / 100
 
 	// 返回评分结果
 	return scoreResult{
@@ -87,5 +116,7 @@ func ScoreBenchmarkResult(r jsonResult) scoreResult {
 		ErrorRate:   Round(errorRateScore, 2),
 		Latency:     Round(latencyScore, 2),
 		Qps:         Round(qpsScore, 2),
+		LossRate:    Round(lossRateScore, 2),
+		Source:      r,
 	}
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+// RankMode 选择 RankResults 使用的排序策略。
+type RankMode string
+
+const (
+	// RankByTotalScore 按 ScoreBenchmarkResult 计算出的总分从高到低排序。
+	RankByTotalScore RankMode = "total"
+	// RankBySpeedOrder 按平均延迟从低到高排序，适合只关心“哪个最快”的场景。
+	RankBySpeedOrder RankMode = "speed"
+	// RankByP95 按 P95 延迟从低到高排序，适合对长尾延迟敏感的场景。
+	RankByP95 RankMode = "p95"
+)
+
+var rankModeFlag = flag.String("rank-mode", string(RankByTotalScore), "结果排序模式：total（总分）、speed（平均延迟）或 p95（P95 延迟）")
+
+// RankResults 对一组 jsonResult 排序。speed 和 p95 模式在排序前先按 runID
+// 派生出的确定性种子做一次洗牌，再执行稳定排序：当多个服务器延迟几乎
+// 相同时（地理位置相近的解析器之间很常见），平局的胜负会随 runID 变化，
+// 而不会系统性地偏向配置文件里排在前面的服务器。
+func RankResults(results []jsonResult, mode RankMode, runID string) []jsonResult {
+	ranked := make([]jsonResult, len(results))
+	copy(ranked, results)
+
+	switch mode {
+	case RankBySpeedOrder:
+		shuffleDeterministic(ranked, runID)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].LatencyStats.MeanMs < ranked[j].LatencyStats.MeanMs
+		})
+	case RankByP95:
+		shuffleDeterministic(ranked, runID)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].LatencyStats.P95Ms < ranked[j].LatencyStats.P95Ms
+		})
+	default: // RankByTotalScore
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ScoreBenchmarkResult(ranked[i]).Total > ScoreBenchmarkResult(ranked[j]).Total
+		})
+	}
+
+	return ranked
+}
+
+// shuffleDeterministic 用 runID 派生的种子对 results 做原地洗牌，
+// 使得平局打散的方式在同一次运行内可复现，但不同运行之间不同。
+func shuffleDeterministic(results []jsonResult, runID string) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(runID))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+	rng.Shuffle(len(results), func(i, j int) {
+		results[i], results[j] = results[j], results[i]
+	})
+}
@@ -0,0 +1,95 @@
+package main
+
+import "testing"
+
+func newRankResult(meanMS, p95MS, marker float64) jsonResult {
+	return jsonResult{
+		LatencyStats:     LatencyStats{MeanMs: meanMS, P95Ms: p95MS},
+		QueriesPerSecond: marker, // used purely as an identity marker in tests
+	}
+}
+
+func TestRankResultsBySpeedOrder(t *testing.T) {
+	results := []jsonResult{
+		newRankResult(30, 60, 3),
+		newRankResult(10, 20, 1),
+		newRankResult(20, 40, 2),
+	}
+
+	ranked := RankResults(results, RankBySpeedOrder, "run-1")
+	want := []float64{1, 2, 3}
+	for i, w := range want {
+		if ranked[i].QueriesPerSecond != w {
+			t.Fatalf("ranked[%d] marker = %v, want %v", i, ranked[i].QueriesPerSecond, w)
+		}
+	}
+}
+
+func TestRankResultsByP95(t *testing.T) {
+	results := []jsonResult{
+		newRankResult(10, 60, 3),
+		newRankResult(30, 20, 1),
+		newRankResult(20, 40, 2),
+	}
+
+	ranked := RankResults(results, RankByP95, "run-1")
+	want := []float64{1, 2, 3}
+	for i, w := range want {
+		if ranked[i].QueriesPerSecond != w {
+			t.Fatalf("ranked[%d] marker = %v, want %v", i, ranked[i].QueriesPerSecond, w)
+		}
+	}
+}
+
+func TestShuffleDeterministicIsReproducibleForSameRunID(t *testing.T) {
+	base := []jsonResult{
+		newRankResult(10, 10, 1),
+		newRankResult(10, 10, 2),
+		newRankResult(10, 10, 3),
+		newRankResult(10, 10, 4),
+		newRankResult(10, 10, 5),
+	}
+
+	a := append([]jsonResult(nil), base...)
+	b := append([]jsonResult(nil), base...)
+	shuffleDeterministic(a, "same-run-id")
+	shuffleDeterministic(b, "same-run-id")
+
+	for i := range a {
+		if a[i].QueriesPerSecond != b[i].QueriesPerSecond {
+			t.Fatalf("shuffleDeterministic with the same runID produced different orders at index %d: %v vs %v", i, a[i].QueriesPerSecond, b[i].QueriesPerSecond)
+		}
+	}
+}
+
+func TestShuffleDeterministicVariesAcrossRunIDs(t *testing.T) {
+	base := []jsonResult{
+		newRankResult(10, 10, 1),
+		newRankResult(10, 10, 2),
+		newRankResult(10, 10, 3),
+		newRankResult(10, 10, 4),
+		newRankResult(10, 10, 5),
+	}
+
+	firstOrder := append([]jsonResult(nil), base...)
+	shuffleDeterministic(firstOrder, "run-a")
+
+	differed := false
+	for _, runID := range []string{"run-b", "run-c", "run-d", "run-e", "run-f"} {
+		other := append([]jsonResult(nil), base...)
+		shuffleDeterministic(other, runID)
+		for i := range other {
+			if other[i].QueriesPerSecond != firstOrder[i].QueriesPerSecond {
+				differed = true
+				break
+			}
+		}
+		if differed {
+			break
+		}
+	}
+
+	if !differed {
+		t.Fatal("shuffleDeterministic produced the same order for every runID tried; tie-break is not varying across runs")
+	}
+}
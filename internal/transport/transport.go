@@ -0,0 +1,293 @@
+// Package transport 封装了对 DNS 上游的多协议访问：明文 UDP/TCP、
+// DNS-over-TLS（DoT）、DNS-over-HTTPS（DoH）和 DNS-over-QUIC（DoQ）。
+// 上层基准测试代码只依赖 Transport 接口，不关心具体协议的连接细节。
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+)
+
+// Protocol 标识服务器地址解析出的传输协议。
+type Protocol string
+
+const (
+	ProtocolUDP Protocol = "udp"
+	ProtocolTCP Protocol = "tcp"
+	ProtocolDoT Protocol = "dot"
+	ProtocolDoH Protocol = "doh"
+	ProtocolDoQ Protocol = "doq"
+)
+
+const (
+	defaultPlainPort = "53"
+	defaultTLSPort   = "853"
+	defaultHTTPSPort = "443"
+	defaultDoHPath   = "/dns-query"
+)
+
+// Transport 是对单个 DNS 上游发起一次查询的抽象，屏蔽了协议差异。
+type Transport interface {
+	// Exchange 发送 msg 并等待响应，返回响应报文和端到端耗时。
+	Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error)
+	// Protocol 返回该 Transport 所使用的协议，用于回填 jsonResult.Protocol。
+	Protocol() Protocol
+	// Close 释放底层连接（TLS/QUIC 会话、HTTP 连接池等）。
+	Close() error
+}
+
+// ParseServer 按 dnspyre 风格解析服务器地址：
+//
+//	<ip>[:port]               -> UDP，默认端口 53
+//	tls://host[:port]         -> DoT，默认端口 853
+//	https://host[:port][/path] -> DoH，默认路径 /dns-query
+//	quic://host[:port]        -> DoQ，默认端口 853
+func ParseServer(server string) (proto Protocol, addr string, err error) {
+	switch {
+	case strings.HasPrefix(server, "tls://"):
+		host := strings.TrimPrefix(server, "tls://")
+		return ProtocolDoT, withDefaultPort(host, defaultTLSPort), nil
+	case strings.HasPrefix(server, "quic://"):
+		host := strings.TrimPrefix(server, "quic://")
+		return ProtocolDoQ, withDefaultPort(host, defaultTLSPort), nil
+	case strings.HasPrefix(server, "https://"):
+		u, parseErr := url.Parse(server)
+		if parseErr != nil {
+			return "", "", fmt.Errorf("解析 DoH 地址失败: %w", parseErr)
+		}
+		if u.Path == "" {
+			u.Path = defaultDoHPath
+		}
+		if u.Port() == "" {
+			// DoH 跑在普通 HTTPS 之上，默认端口是 443，不是 DoT/DoQ 的 853。
+			u.Host = net.JoinHostPort(u.Hostname(), defaultHTTPSPort)
+		}
+		return ProtocolDoH, u.String(), nil
+	default:
+		return ProtocolUDP, withDefaultPort(server, defaultPlainPort), nil
+	}
+}
+
+func withDefaultPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// New 根据 server 地址构造对应协议的 Transport。useTCP 仅在地址被解析为
+// 明文协议时生效，用于强制通过 TCP 而非 UDP 发送查询。
+func New(server string, useTCP bool, timeout time.Duration) (Transport, error) {
+	proto, addr, err := ParseServer(server)
+	if err != nil {
+		return nil, err
+	}
+
+	switch proto {
+	case ProtocolUDP:
+		if useTCP {
+			return newDNSClientTransport(ProtocolTCP, addr, "tcp", timeout), nil
+		}
+		return newDNSClientTransport(ProtocolUDP, addr, "udp", timeout), nil
+	case ProtocolDoT:
+		return newDNSClientTransport(ProtocolDoT, addr, "tcp-tls", timeout), nil
+	case ProtocolDoH:
+		return newDoHTransport(addr, timeout), nil
+	case ProtocolDoQ:
+		return newDoQTransport(addr, timeout), nil
+	default:
+		return nil, fmt.Errorf("未知协议: %s", proto)
+	}
+}
+
+// dnsClientTransport 基于 miekg/dns.Client，承载 UDP、TCP 和 DoT。
+type dnsClientTransport struct {
+	proto  Protocol
+	addr   string
+	client *dns.Client
+}
+
+func newDNSClientTransport(proto Protocol, addr, net string, timeout time.Duration) *dnsClientTransport {
+	c := &dns.Client{Net: net, Timeout: timeout}
+	if proto == ProtocolDoT {
+		c.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return &dnsClientTransport{proto: proto, addr: addr, client: c}
+}
+
+func (t *dnsClientTransport) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error) {
+	resp, rtt, err := t.client.ExchangeContext(ctx, msg, t.addr)
+	return resp, rtt, err
+}
+
+func (t *dnsClientTransport) Protocol() Protocol { return t.proto }
+
+func (t *dnsClientTransport) Close() error { return nil }
+
+// dohTransport 通过 net/http 以 DNS-over-HTTPS (RFC 8484) 方式发送查询。
+type dohTransport struct {
+	url    string
+	client *http.Client
+}
+
+func newDoHTransport(addr string, timeout time.Duration) *dohTransport {
+	return &dohTransport{
+		url:    addr,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *dohTransport) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error) {
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("打包 DNS 报文失败: %w", err)
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, strings.NewReader(string(packed)))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	rtt := time.Since(start)
+	if err != nil {
+		return nil, rtt, err
+	}
+
+	reply := new(dns.Msg)
+	if err := reply.Unpack(body); err != nil {
+		return nil, rtt, fmt.Errorf("解析 DoH 响应失败: %w", err)
+	}
+	return reply, rtt, nil
+}
+
+func (t *dohTransport) Protocol() Protocol { return ProtocolDoH }
+
+func (t *dohTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// doqTransport 通过 quic-go 以 DNS-over-QUIC (RFC 9250) 方式发送查询，
+// 每次查询使用一个新的双向流。Exchange 可能被多个 goroutine 并发调用，
+// 因此连接的懒初始化用 mutex 保护，而不是裸的 check-then-set。
+type doqTransport struct {
+	addr    string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn quic.Connection
+}
+
+func newDoQTransport(addr string, timeout time.Duration) *doqTransport {
+	return &doqTransport{addr: addr, timeout: timeout}
+}
+
+func (t *doqTransport) dial(ctx context.Context) (quic.Connection, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn != nil {
+		return t.conn, nil
+	}
+	conn, err := quic.DialAddr(ctx, t.addr, &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"doq"},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("建立 QUIC 连接失败: %w", err)
+	}
+	t.conn = conn
+	return conn, nil
+}
+
+// dropConn 清除一个已失效的连接，但仅当它仍是当前缓存的连接时才清除，
+// 避免和另一个并发 goroutine 刚刚建立的新连接互相踩踏。下一次
+// Exchange 会通过 dial 重新建立连接。
+func (t *doqTransport) dropConn(failed quic.Connection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == failed {
+		t.conn = nil
+	}
+}
+
+func (t *doqTransport) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, time.Duration, error) {
+	start := time.Now()
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		// 对同一个被测服务器的网络抖动或空闲超时会让 QUIC 连接死掉；
+		// 如果缓存了这个死连接，后续每一次查询都会在它上面重复失败，
+		// 被误报为该服务器持续 100% 丢包。清掉它，下次查询会重新拨号。
+		t.dropConn(conn)
+		return nil, 0, fmt.Errorf("打开 QUIC 流失败: %w", err)
+	}
+	defer stream.Close()
+
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("打包 DNS 报文失败: %w", err)
+	}
+	// DoQ 要求一个 2 字节大端长度前缀，复用 TCP 上已有的编码方式。
+	prefixed := append([]byte{byte(len(packed) >> 8), byte(len(packed))}, packed...)
+	if _, err := stream.Write(prefixed); err != nil {
+		t.dropConn(conn)
+		return nil, 0, err
+	}
+	_ = stream.Close()
+
+	body, err := io.ReadAll(stream)
+	rtt := time.Since(start)
+	if err != nil {
+		t.dropConn(conn)
+		return nil, rtt, err
+	}
+	if len(body) < 2 {
+		return nil, rtt, fmt.Errorf("DoQ 响应过短")
+	}
+
+	reply := new(dns.Msg)
+	if err := reply.Unpack(body[2:]); err != nil {
+		return nil, rtt, fmt.Errorf("解析 DoQ 响应失败: %w", err)
+	}
+	return reply, rtt, nil
+}
+
+func (t *doqTransport) Protocol() Protocol { return ProtocolDoQ }
+
+func (t *doqTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		return nil
+	}
+	return t.conn.CloseWithError(0, "")
+}
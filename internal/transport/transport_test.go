@@ -0,0 +1,33 @@
+package transport
+
+import "testing"
+
+func TestParseServer(t *testing.T) {
+	cases := []struct {
+		server    string
+		wantProto Protocol
+		wantAddr  string
+	}{
+		{"8.8.8.8", ProtocolUDP, "8.8.8.8:53"},
+		{"8.8.8.8:5353", ProtocolUDP, "8.8.8.8:5353"},
+		{"tls://dns.google", ProtocolDoT, "dns.google:853"},
+		{"tls://dns.google:8853", ProtocolDoT, "dns.google:8853"},
+		{"quic://dns.adguard.com", ProtocolDoQ, "dns.adguard.com:853"},
+		{"https://dns.google/dns-query", ProtocolDoH, "https://dns.google:443/dns-query"},
+		{"https://cloudflare-dns.com", ProtocolDoH, "https://cloudflare-dns.com:443/dns-query"},
+		{"https://dns.google:8443/dns-query", ProtocolDoH, "https://dns.google:8443/dns-query"},
+	}
+
+	for _, c := range cases {
+		proto, addr, err := ParseServer(c.server)
+		if err != nil {
+			t.Fatalf("ParseServer(%q) returned error: %v", c.server, err)
+		}
+		if proto != c.wantProto {
+			t.Errorf("ParseServer(%q) protocol = %q, want %q", c.server, proto, c.wantProto)
+		}
+		if addr != c.wantAddr {
+			t.Errorf("ParseServer(%q) addr = %q, want %q", c.server, addr, c.wantAddr)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package wrr 实现 Nginx 风格的平滑加权轮询（Smooth Weighted Round-Robin）选择器，
+// 用于在多个 DNS 上游之间生成可重复、均匀交织的调度序列。
+package wrr
+
+import (
+	"sync"
+)
+
+// Peer 表示一个参与轮询的 DNS 上游服务器及其固定权重。
+type Peer struct {
+	Server string
+	Weight int
+}
+
+// peerState 保存单个 Peer 的运行时状态：effectiveWeight 可被动态调整，
+// currentWeight 是算法每轮累加/回退的可变状态。
+type peerState struct {
+	server          string
+	effectiveWeight int
+	currentWeight   int
+}
+
+// Selector 是协程安全的平滑加权轮询选择器。算法：每次 Next 调用时，
+// 对每个 peer 执行 currentWeight += effectiveWeight，选出 currentWeight
+// 最大的 peer，再从其 currentWeight 中减去所有 effectiveWeight 之和。
+// 例如权重 {5,1,1} 会产生 a,a,b,a,c,a,a 这样均匀交织的序列，而不是
+// 集中出现的 a,a,a,a,a,b,c。
+type Selector struct {
+	mu    sync.Mutex
+	peers []*peerState
+}
+
+// New 创建一个 Selector，初始填充给定的 peers。
+func New(peers ...Peer) *Selector {
+	s := &Selector{}
+	for _, p := range peers {
+		s.peers = append(s.peers, &peerState{server: p.Server, effectiveWeight: p.Weight})
+	}
+	return s
+}
+
+// Add 添加一个新的 peer，若 server 已存在则更新其 effectiveWeight。
+// 这也是未来动态 WRR 根据 jsonResult 中观测到的延迟/错误率调整权重的入口。
+func (s *Selector) Add(server string, weight int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.peers {
+		if p.server == server {
+			p.effectiveWeight = weight
+			return
+		}
+	}
+	s.peers = append(s.peers, &peerState{server: server, effectiveWeight: weight})
+}
+
+// Remove 移除指定 server 对应的 peer。
+func (s *Selector) Remove(server string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.peers {
+		if p.server == server {
+			s.peers = append(s.peers[:i], s.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Reset 清空所有 peer 的 currentWeight，但保留 effectiveWeight 和 peer 列表，
+// 用于在两轮基准测试之间重新开始一个确定性的调度序列。
+func (s *Selector) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.peers {
+		p.currentWeight = 0
+	}
+}
+
+// Next 返回下一个应被调度的 server，按平滑加权轮询算法选择。
+// 没有任何 peer 时返回 ok=false。
+func (s *Selector) Next() (server string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.peers) == 0 {
+		return "", false
+	}
+
+	total := 0
+	var best *peerState
+	for _, p := range s.peers {
+		p.currentWeight += p.effectiveWeight
+		total += p.effectiveWeight
+		if best == nil || p.currentWeight > best.currentWeight {
+			best = p
+		}
+	}
+
+	best.currentWeight -= total
+	return best.server, true
+}
@@ -0,0 +1,49 @@
+package wrr
+
+import "testing"
+
+func TestSelectorNextSmoothSequence(t *testing.T) {
+	s := New(
+		Peer{Server: "a", Weight: 5},
+		Peer{Server: "b", Weight: 1},
+		Peer{Server: "c", Weight: 1},
+	)
+
+	want := []string{"a", "a", "b", "a", "c", "a", "a"}
+	for i, w := range want {
+		got, ok := s.Next()
+		if !ok {
+			t.Fatalf("Next() #%d: ok = false, want true", i)
+		}
+		if got != w {
+			t.Fatalf("Next() #%d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestSelectorNextEmpty(t *testing.T) {
+	s := New()
+	if _, ok := s.Next(); ok {
+		t.Fatal("Next() on empty selector: ok = true, want false")
+	}
+}
+
+func TestSelectorAddRemoveReset(t *testing.T) {
+	s := New(Peer{Server: "a", Weight: 1})
+	s.Add("b", 1)
+
+	if got, _ := s.Next(); got != "a" {
+		t.Fatalf("Next() = %q, want %q", got, "a")
+	}
+
+	s.Remove("a")
+	if got, _ := s.Next(); got != "b" {
+		t.Fatalf("Next() after Remove(a) = %q, want %q", got, "b")
+	}
+
+	s.Reset()
+	s.Add("b", 1)
+	if got, _ := s.Next(); got != "b" {
+		t.Fatalf("Next() after Reset() = %q, want %q", got, "b")
+	}
+}